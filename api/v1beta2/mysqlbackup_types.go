@@ -0,0 +1,148 @@
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupConditionType represents the condition type of a MySQLBackup.
+type BackupConditionType string
+
+const (
+	// BackupConditionReady indicates the backup finished successfully.
+	BackupConditionReady BackupConditionType = "Ready"
+	// BackupConditionFailed indicates the backup Job failed.
+	BackupConditionFailed BackupConditionType = "Failed"
+	// BackupConditionInProgress indicates the backup Job is still running.
+	BackupConditionInProgress BackupConditionType = "InProgress"
+)
+
+// BackupCondition describes the state of a MySQLBackup at a certain point.
+type BackupCondition struct {
+	// Type is the type of the condition.
+	Type BackupConditionType `json:"type"`
+	// Status is the status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// MySQLBackupSpec defines the desired state of MySQLBackup.
+type MySQLBackupSpec struct {
+	// ClusterName is the name of the MySQLCluster to back up.
+	// The MySQLCluster must exist in the same namespace as this MySQLBackup.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// JobConfig specifies the object storage bucket to store the backup
+	// artifact in and the ServiceAccount used to run the backup Job. The
+	// Job's container image and resources are not configurable here.
+	// +optional
+	JobConfig *BackupJobConfig `json:"jobConfig,omitempty"`
+}
+
+// BackupJobConfig configures the Job created to perform a backup.
+type BackupJobConfig struct {
+	// BucketName is the name of the object storage bucket to upload the backup to.
+	// +kubebuilder:validation:Required
+	BucketName string `json:"bucketName"`
+
+	// ServiceAccountName is the name of the ServiceAccount used to run the backup Job.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// MySQLBackupStatus defines the observed state of MySQLBackup.
+type MySQLBackupStatus struct {
+	// Completed is true once the backup Job has finished, regardless of
+	// whether it succeeded or failed.
+	Completed bool `json:"completed"`
+
+	// Conditions is an array of conditions describing the current state of
+	// the backup.
+	// +optional
+	Conditions []BackupCondition `json:"conditions,omitempty"`
+
+	// BinlogFilename is the binlog filename recorded at backup time.
+	// +optional
+	BinlogFilename string `json:"binlogFilename,omitempty"`
+	// BinlogPosition is the binlog position recorded at backup time.
+	// +optional
+	BinlogPosition int64 `json:"binlogPosition,omitempty"`
+	// GTIDSet is the GTID set recorded at backup time.
+	// +optional
+	GTIDSet string `json:"gtidSet,omitempty"`
+	// BackupSize is the size in bytes of the uploaded backup artifact.
+	// +optional
+	BackupSize int64 `json:"backupSize,omitempty"`
+	// Elapsed is the time the backup Job took to complete.
+	// +optional
+	Elapsed *metav1.Duration `json:"elapsed,omitempty"`
+
+	// JobName is the name of the Job created to perform this backup.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+}
+
+// GetCondition returns a pointer to the condition of the given type, or nil
+// if it does not exist.
+func (s *MySQLBackupStatus) GetCondition(t BackupConditionType) *BackupCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates a condition of the given type, bumping
+// LastTransitionTime only when the status actually changes.
+func (s *MySQLBackupStatus) SetCondition(c BackupCondition) {
+	existing := s.GetCondition(c.Type)
+	if existing == nil {
+		s.Conditions = append(s.Conditions, c)
+		return
+	}
+	if existing.Status != c.Status {
+		existing.LastTransitionTime = c.LastTransitionTime
+	}
+	existing.Status = c.Status
+	existing.Reason = c.Reason
+	existing.Message = c.Message
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=mysqlbak
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MySQLBackup is the Schema for the mysqlbackups API. It represents a
+// single, one-shot backup attempt for a referenced MySQLCluster.
+type MySQLBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MySQLBackupSpec   `json:"spec,omitempty"`
+	Status MySQLBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MySQLBackupList contains a list of MySQLBackup.
+type MySQLBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MySQLBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MySQLBackup{}, &MySQLBackupList{})
+}
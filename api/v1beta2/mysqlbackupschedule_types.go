@@ -0,0 +1,79 @@
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MySQLBackupScheduleSpec defines the desired state of MySQLBackupSchedule.
+type MySQLBackupScheduleSpec struct {
+	// ClusterName is the name of the MySQLCluster to back up on a schedule.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// Schedule is a Cron expression, e.g. "0 */6 * * *", specifying when to
+	// create a new MySQLBackup.
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Suspend tells the controller to stop creating new MySQLBackup objects.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// BackupTemplate is used to fill in the Spec of each created MySQLBackup.
+	// +kubebuilder:validation:Required
+	BackupTemplate MySQLBackupSpec `json:"backupTemplate"`
+
+	// SuccessfulBackupsHistoryLimit is the number of completed MySQLBackups to
+	// keep. Older ones are pruned. Defaults to 3.
+	// +optional
+	// +kubebuilder:default=3
+	SuccessfulBackupsHistoryLimit int32 `json:"successfulBackupsHistoryLimit,omitempty"`
+
+	// FailedBackupsHistoryLimit is the number of failed MySQLBackups to keep.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:default=1
+	FailedBackupsHistoryLimit int32 `json:"failedBackupsHistoryLimit,omitempty"`
+}
+
+// MySQLBackupScheduleStatus defines the observed state of MySQLBackupSchedule.
+type MySQLBackupScheduleStatus struct {
+	// LastScheduleTime is the last time a MySQLBackup was created for this schedule.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastBackupName is the name of the most recently created MySQLBackup.
+	// +optional
+	LastBackupName string `json:"lastBackupName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MySQLBackupSchedule is the Schema for the mysqlbackupschedules API. It
+// periodically creates MySQLBackup objects for a referenced MySQLCluster,
+// superseding the backup schedule field that used to live on MySQLCluster.
+type MySQLBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MySQLBackupScheduleSpec   `json:"spec,omitempty"`
+	Status MySQLBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MySQLBackupScheduleList contains a list of MySQLBackupSchedule.
+type MySQLBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MySQLBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MySQLBackupSchedule{}, &MySQLBackupScheduleList{})
+}
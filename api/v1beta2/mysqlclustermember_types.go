@@ -0,0 +1,120 @@
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MySQLClusterMemberSpec defines the desired state of MySQLClusterMember.
+// It lives in the "hub" cluster and tells the controller how to reach a
+// set of instances that are actually running in a remote Kubernetes
+// cluster.
+type MySQLClusterMemberSpec struct {
+	// ClusterName is the name of the MySQLCluster this member belongs to.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// KubeconfigSecretName is the name of a Secret, in the same namespace as
+	// this MySQLClusterMember, holding a kubeconfig for the remote cluster
+	// under the key "kubeconfig". It is created by
+	// `kubectl moco multicluster setup`.
+	// +kubebuilder:validation:Required
+	KubeconfigSecretName string `json:"kubeconfigSecretName"`
+
+	// Indices lists the instance indices of the MySQLCluster that are hosted
+	// in the remote cluster referenced by KubeconfigSecretName.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Indices []int `json:"indices"`
+
+	// PodAddressMode selects how the controller reaches agent pods in the
+	// remote cluster.
+	// +kubebuilder:validation:Enum=Mesh;ExternalName;LoadBalancer
+	// +kubebuilder:default=Mesh
+	// +optional
+	PodAddressMode PodAddressMode `json:"podAddressMode,omitempty"`
+
+	// PeerUserSecretName is the name of a Secret, in the same namespace as
+	// this MySQLClusterMember, holding the replication user's password
+	// under the key "PASSWORD". When set together with
+	// ReplicationSourceSecretName, and the current primary is one of
+	// Indices, the controller mirrors it into ReplicationSourceSecretName
+	// so the local MySQLCluster's intermediate-primary replication can be
+	// configured without an operator copying credentials across clusters
+	// by hand.
+	// +optional
+	PeerUserSecretName string `json:"peerUserSecretName,omitempty"`
+
+	// ReplicationSourceSecretName is the name of the Secret, in the same
+	// namespace as this MySQLClusterMember, to populate with PRIMARY_HOST,
+	// PRIMARY_PORT, PRIMARY_USER and PRIMARY_PASSWORD once PeerUserSecretName
+	// is set and the current primary is hosted in this member.
+	// +optional
+	ReplicationSourceSecretName string `json:"replicationSourceSecretName,omitempty"`
+
+	// PrimaryUser is the replication user name recorded in
+	// ReplicationSourceSecretName.
+	// +optional
+	// +kubebuilder:default=root
+	PrimaryUser string `json:"primaryUser,omitempty"`
+
+	// PrimaryMySQLPort is the mysqld port recorded in
+	// ReplicationSourceSecretName.
+	// +optional
+	// +kubebuilder:default=3306
+	PrimaryMySQLPort int `json:"primaryMySQLPort,omitempty"`
+}
+
+// PodAddressMode selects the mechanism used to dial a remote instance's agent.
+type PodAddressMode string
+
+const (
+	// PodAddressModeMesh dials the pod directly through a cross-cluster service mesh.
+	PodAddressModeMesh PodAddressMode = "Mesh"
+	// PodAddressModeExternalName dials through an ExternalName Service pointed at the remote cluster's ingress.
+	PodAddressModeExternalName PodAddressMode = "ExternalName"
+	// PodAddressModeLoadBalancer dials through a LoadBalancer Service exposed by the remote cluster.
+	PodAddressModeLoadBalancer PodAddressMode = "LoadBalancer"
+)
+
+// MySQLClusterMemberStatus defines the observed state of MySQLClusterMember.
+type MySQLClusterMemberStatus struct {
+	// Reachable is true if the controller last succeeded in dialing at least
+	// one instance in the remote cluster.
+	// +optional
+	Reachable bool `json:"reachable,omitempty"`
+
+	// LastSeenTime is the last time Reachable was computed.
+	// +optional
+	LastSeenTime *metav1.Time `json:"lastSeenTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Reachable",type="boolean",JSONPath=".status.reachable"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MySQLClusterMember is the Schema for the mysqlclustermembers API. It
+// extends a MySQLCluster defined in the hub cluster with a subset of
+// instance indices that are actually hosted in a remote Kubernetes cluster,
+// enabling replication topologies that span clusters and regions.
+type MySQLClusterMember struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MySQLClusterMemberSpec   `json:"spec,omitempty"`
+	Status MySQLClusterMemberStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MySQLClusterMemberList contains a list of MySQLClusterMember.
+type MySQLClusterMemberList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MySQLClusterMember `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MySQLClusterMember{}, &MySQLClusterMemberList{})
+}
@@ -0,0 +1,182 @@
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DataScriptTarget selects which instances of a MySQLCluster a
+// MySQLDataScript runs against.
+type DataScriptTarget string
+
+const (
+	// DataScriptTargetPrimary runs the script against the primary instance only.
+	DataScriptTargetPrimary DataScriptTarget = "Primary"
+	// DataScriptTargetAllReplicas runs the script against every replica instance.
+	DataScriptTargetAllReplicas DataScriptTarget = "AllReplicas"
+	// DataScriptTargetIndex runs the script against a single instance selected by index.
+	DataScriptTargetIndex DataScriptTarget = "Index"
+)
+
+// MySQLDataScriptSpec defines the desired state of MySQLDataScript.
+type MySQLDataScriptSpec struct {
+	// ClusterName is the name of the MySQLCluster to run the script against.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName"`
+
+	// Script is the SQL script to execute, one or more statements separated
+	// by semicolons.
+	// +kubebuilder:validation:Required
+	Script string `json:"script"`
+
+	// Target selects which instances to run the script against. Defaults to Primary.
+	// +kubebuilder:validation:Enum=Primary;AllReplicas;Index
+	// +kubebuilder:default=Primary
+	// +optional
+	Target DataScriptTarget `json:"target,omitempty"`
+
+	// Index selects the instance to run against when Target is Index.
+	// +optional
+	Index *int `json:"index,omitempty"`
+
+	// PreconditionQuery, if set, is run before Script on each target instance.
+	// A single-row, single-column result of "1" allows the script to run;
+	// any other result skips that instance, making the script idempotent.
+	// +optional
+	PreconditionQuery string `json:"preconditionQuery,omitempty"`
+
+	// Timeout bounds how long a single instance's execution may take.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// DryRun parses and validates the script without committing any changes.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// AbortOnError skips the remaining instances once one instance fails.
+	// Defaults to true. Within an instance, the first failing statement ends
+	// that instance's run whether or not this is set.
+	// +optional
+	// +kubebuilder:default=true
+	AbortOnError bool `json:"abortOnError,omitempty"`
+}
+
+// DataScriptStatementResult records the outcome of running the script
+// against a single instance.
+type DataScriptStatementResult struct {
+	// Index is the instance index this result belongs to.
+	Index int `json:"index"`
+	// Succeeded is true if the script completed without error on this instance.
+	Succeeded bool `json:"succeeded"`
+	// Skipped is true if PreconditionQuery caused this instance to be skipped.
+	Skipped bool `json:"skipped,omitempty"`
+	// RowsAffected is the cumulative rows affected across all statements.
+	RowsAffected int64 `json:"rowsAffected,omitempty"`
+	// ExecutionTime is how long the script took to run on this instance.
+	ExecutionTime *metav1.Duration `json:"executionTime,omitempty"`
+	// Error contains the error message if Succeeded is false.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// DataScriptConditionType represents the condition type of a MySQLDataScript.
+type DataScriptConditionType string
+
+const (
+	// DataScriptConditionReady indicates every targeted instance succeeded.
+	DataScriptConditionReady DataScriptConditionType = "Ready"
+	// DataScriptConditionFailed indicates at least one targeted instance failed.
+	DataScriptConditionFailed DataScriptConditionType = "Failed"
+)
+
+// DataScriptCondition describes the state of a MySQLDataScript at a certain point.
+type DataScriptCondition struct {
+	// Type is the type of the condition.
+	Type DataScriptConditionType `json:"type"`
+	// Status is the status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// MySQLDataScriptStatus defines the observed state of MySQLDataScript.
+type MySQLDataScriptStatus struct {
+	// Completed is true once execution has finished on every targeted
+	// instance, or has stopped early because AbortOnError is set and an
+	// instance failed.
+	Completed bool `json:"completed"`
+
+	// Conditions is an array of conditions describing the current state of
+	// the script execution.
+	// +optional
+	Conditions []DataScriptCondition `json:"conditions,omitempty"`
+
+	// Results holds one entry per targeted instance.
+	// +optional
+	Results []DataScriptStatementResult `json:"results,omitempty"`
+}
+
+// GetCondition returns a pointer to the condition of the given type, or nil
+// if it does not exist.
+func (s *MySQLDataScriptStatus) GetCondition(t DataScriptConditionType) *DataScriptCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates a condition of the given type, bumping
+// LastTransitionTime only when the status actually changes.
+func (s *MySQLDataScriptStatus) SetCondition(c DataScriptCondition) {
+	existing := s.GetCondition(c.Type)
+	if existing == nil {
+		s.Conditions = append(s.Conditions, c)
+		return
+	}
+	if existing.Status != c.Status {
+		existing.LastTransitionTime = c.LastTransitionTime
+	}
+	existing.Status = c.Status
+	existing.Reason = c.Reason
+	existing.Message = c.Message
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target"
+// +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MySQLDataScript is the Schema for the mysqldatascripts API. It runs a
+// user-provided SQL script against selected instances of a referenced
+// MySQLCluster via the moco-agent sidecar, so that migrations don't
+// require an externally wired Job or kubectl exec.
+type MySQLDataScript struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MySQLDataScriptSpec   `json:"spec,omitempty"`
+	Status MySQLDataScriptStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MySQLDataScriptList contains a list of MySQLDataScript.
+type MySQLDataScriptList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MySQLDataScript `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MySQLDataScript{}, &MySQLDataScriptList{})
+}
@@ -2,9 +2,10 @@ package clustering
 
 import (
 	"context"
-	"io"
+	"fmt"
 	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	agent "github.com/cybozu-go/moco-agent/proto"
@@ -12,50 +13,218 @@ import (
 	"github.com/cybozu-go/moco/pkg/cert"
 	"github.com/cybozu-go/moco/pkg/constants"
 	"github.com/cybozu-go/moco/pkg/dbop"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
-// AgentConn represents a gRPC connection to a moco-agent
+var (
+	cachedConnsMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "moco",
+		Name:      "agent_conn_cache_size",
+		Help:      "The number of cached agent gRPC connections.",
+	})
+	evictedConnsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "moco",
+		Name:      "agent_conn_cache_evictions_total",
+		Help:      "The number of times a cached agent gRPC connection was evicted.",
+	}, []string{"reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cachedConnsMetric, evictedConnsMetric)
+}
+
+// AgentConn represents a gRPC connection to a moco-agent. Close releases the
+// caller's reference; the underlying connection is only torn down once no
+// caller holds it.
 type AgentConn interface {
 	agent.AgentClient
-	io.Closer
+	Close() error
+	// RawConn returns the underlying *grpc.ClientConn, for RPCs (such as
+	// ExecSQL, see pkg/agentproto) that aren't part of agent.AgentClient.
+	RawConn() *grpc.ClientConn
+}
+
+// agentKey identifies a single moco-agent endpoint within the connection cache.
+type agentKey struct {
+	clusterUID types.UID
+	index      int
 }
 
-type agentConn struct {
+// pooledConn wraps a *grpc.ClientConn with the bookkeeping needed to share
+// it across callers and to detect when it must be replaced.
+type pooledConn struct {
 	agent.AgentClient
-	*grpc.ClientConn
+	conn    *grpc.ClientConn
+	addr    string
+	refs    int
+	factory *defaultAgentFactory
+	key     agentKey
 }
 
-var _ AgentConn = agentConn{}
+var _ AgentConn = (*pooledConn)(nil)
+
+// RawConn returns the underlying *grpc.ClientConn.
+func (c *pooledConn) RawConn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close decrements the reference count and tears down the underlying
+// connection only once the last caller has released it and it is no longer
+// referenced from the cache.
+func (c *pooledConn) Close() error {
+	f := c.factory
+	f.mu.Lock()
+	c.refs--
+	refs := c.refs
+	// Compare by identity, not just key presence: once this connection has
+	// been evicted, a newer pooledConn may already occupy c.key, and that
+	// newer connection being "cached" must not stop us from closing the
+	// stale one we actually hold.
+	current, stillCached := f.conns[c.key]
+	cached := stillCached && current == c
+	f.mu.Unlock()
+
+	if refs > 0 || cached {
+		return nil
+	}
+	return c.conn.Close()
+}
 
 // AgentFactory represents the interface of a factory to create AgentConn
 type AgentFactory interface {
 	New(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (AgentConn, error)
+	// Reset closes and evicts every cached connection for the given cluster.
+	// It is called by the cluster manager on delete/rebuild.
+	Reset(cluster *mocov1beta2.MySQLCluster)
 }
 
 // NewAgentFactory returns a new AgentFactory.
 func NewAgentFactory(r dbop.Resolver, reloader *cert.Reloader) AgentFactory {
-	return defaultAgentFactory{resolver: r, reloader: reloader}
+	return &defaultAgentFactory{
+		resolver: r,
+		reloader: reloader,
+		conns:    make(map[agentKey]*pooledConn),
+	}
+}
+
+// MemberResolver resolves the dial address of an instance that is hosted in
+// a remote Kubernetes cluster, as declared by a MySQLClusterMember. It
+// returns found=false for an index that is hosted locally, in which case
+// the factory falls back to its regular dbop.Resolver.
+type MemberResolver interface {
+	ResolveMember(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (addr string, found bool, err error)
+}
+
+// NewMultiClusterAgentFactory returns an AgentFactory that dials instances
+// hosted in remote Kubernetes clusters (per MySQLClusterMember) through
+// members, falling back to r for instances hosted locally.
+func NewMultiClusterAgentFactory(r dbop.Resolver, reloader *cert.Reloader, members MemberResolver) AgentFactory {
+	return &defaultAgentFactory{
+		resolver: r,
+		reloader: reloader,
+		members:  members,
+		conns:    make(map[agentKey]*pooledConn),
+	}
 }
 
 type defaultAgentFactory struct {
 	resolver dbop.Resolver
 	reloader *cert.Reloader
+	members  MemberResolver
+
+	mu    sync.Mutex
+	conns map[agentKey]*pooledConn
 }
 
-var _ AgentFactory = defaultAgentFactory{}
+var _ AgentFactory = &defaultAgentFactory{}
 
-func (f defaultAgentFactory) New(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (AgentConn, error) {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// New returns an AgentConn for the given cluster instance, reusing a cached
+// connection when one is already READY or IDLE and still points at the
+// instance's current pod IP. Stale connections (TRANSIENT_FAILURE, SHUTDOWN,
+// or a changed pod IP) are evicted and replaced.
+func (f *defaultAgentFactory) New(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (AgentConn, error) {
+	resolveCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	ip, err := f.resolver.Resolve(ctx, cluster, index)
+	addr, err := f.resolveAddr(resolveCtx, cluster, index)
+	if err != nil {
+		return nil, err
+	}
+	key := agentKey{clusterUID: cluster.UID, index: index}
+
+	f.mu.Lock()
+	if pc, ok := f.conns[key]; ok {
+		if pc.addr != addr {
+			f.evictLocked(key, "pod_ip_changed")
+		} else if state := pc.conn.GetState(); state == connectivity.Ready || state == connectivity.Idle {
+			pc.refs++
+			f.mu.Unlock()
+			return pc, nil
+		} else {
+			reason := "transient_failure"
+			if state == connectivity.Shutdown {
+				reason = "shutdown"
+			}
+			f.evictLocked(key, reason)
+		}
+	}
+	f.mu.Unlock()
+
+	conn, err := f.dial(cluster, index, addr)
 	if err != nil {
 		return nil, err
 	}
-	addr := net.JoinHostPort(ip, strconv.Itoa(constants.AgentPort))
+
+	pc := &pooledConn{
+		AgentClient: agent.NewAgentClient(conn),
+		conn:        conn,
+		addr:        addr,
+		refs:        1,
+		factory:     f,
+		key:         key,
+	}
+
+	f.mu.Lock()
+	f.conns[key] = pc
+	cachedConnsMetric.Set(float64(len(f.conns)))
+	f.mu.Unlock()
+
+	return pc, nil
+}
+
+// resolveAddr returns the host:port to dial for the given instance. If
+// members is configured and index is hosted in a remote Kubernetes cluster,
+// its address is used instead of the local dbop.Resolver; the remote
+// connection still authenticates using cluster.PodHostname(index) as the
+// TLS SNI/authority, exactly as a local connection would.
+func (f *defaultAgentFactory) resolveAddr(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (string, error) {
+	if f.members != nil {
+		if addr, found, err := f.members.ResolveMember(ctx, cluster, index); err != nil {
+			return "", fmt.Errorf("unable to resolve remote member for index %d: %w", index, err)
+		} else if found {
+			return addr, nil
+		}
+	}
+
+	ip, err := f.resolver.Resolve(ctx, cluster, index)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(constants.AgentPort)), nil
+}
+
+// dial opens a new connection to addr. The returned connection stays valid
+// across certificate rotations: f.reloader.TLSClientConfig() returns a
+// tls.Config backed by a GetClientCertificate callback that reads the
+// reloader's current certificate under its own lock, so a pooled connection
+// never needs to be rebuilt just because cert.Reloader swapped certs.
+func (f *defaultAgentFactory) dial(cluster *mocov1beta2.MySQLCluster, index int, addr string) (*grpc.ClientConn, error) {
 	kp := keepalive.ClientParameters{
 		Time: 1 * time.Minute,
 	}
@@ -65,10 +234,35 @@ func (f defaultAgentFactory) New(ctx context.Context, cluster *mocov1beta2.MySQL
 		grpc.WithTransportCredentials(cred),
 		grpc.WithKeepaliveParams(kp))
 	if err != nil {
-		return agentConn{}, err
+		return nil, fmt.Errorf("unable to dial agent at %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// Reset closes and evicts every cached connection belonging to the given
+// cluster. Callers still holding a reference continue to work; Close on
+// that reference will tear the connection down once they are done with it.
+func (f *defaultAgentFactory) Reset(cluster *mocov1beta2.MySQLCluster) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key := range f.conns {
+		if key.clusterUID == cluster.UID {
+			f.evictLocked(key, "reset")
+		}
+	}
+}
+
+// evictLocked removes the cached connection for key and closes it if no
+// caller still holds a reference to it. f.mu must be held by the caller.
+func (f *defaultAgentFactory) evictLocked(key agentKey, reason string) {
+	pc, ok := f.conns[key]
+	if !ok {
+		return
+	}
+	delete(f.conns, key)
+	cachedConnsMetric.Set(float64(len(f.conns)))
+	evictedConnsMetric.WithLabelValues(reason).Inc()
+	if pc.refs == 0 {
+		pc.conn.Close()
 	}
-	return agentConn{
-		AgentClient: agent.NewAgentClient(conn),
-		ClientConn:  conn,
-	}, nil
 }
@@ -0,0 +1,153 @@
+package clustering
+
+import (
+	"testing"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestPooledConn builds a pooledConn around a real, lazily-connecting
+// *grpc.ClientConn (grpc.NewClient never dials until an RPC is attempted),
+// so Close/evictLocked/Reset can be exercised without a live agent to talk
+// to.
+func newTestPooledConn(t *testing.T, f *defaultAgentFactory, key agentKey, refs int) *pooledConn {
+	t.Helper()
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unable to build test grpc.ClientConn: %v", err)
+	}
+	return &pooledConn{conn: conn, addr: "127.0.0.1:1", refs: refs, factory: f, key: key}
+}
+
+func newTestFactory() *defaultAgentFactory {
+	return &defaultAgentFactory{conns: make(map[agentKey]*pooledConn)}
+}
+
+func TestPooledConnClose(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "a shared reference is released without closing the connection",
+			run: func(t *testing.T) {
+				f := newTestFactory()
+				key := agentKey{clusterUID: types.UID("cluster-a"), index: 0}
+				pc := newTestPooledConn(t, f, key, 2)
+				f.conns[key] = pc
+
+				if err := pc.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+				if pc.refs != 1 {
+					t.Fatalf("refs = %d, want 1", pc.refs)
+				}
+				if pc.conn.GetState() == connectivity.Shutdown {
+					t.Fatalf("connection was closed while still referenced")
+				}
+				if _, ok := f.conns[key]; !ok {
+					t.Fatalf("connection was evicted from the cache while still cached")
+				}
+			},
+		},
+		{
+			name: "the last reference is released but the connection stays pooled for reuse",
+			run: func(t *testing.T) {
+				f := newTestFactory()
+				key := agentKey{clusterUID: types.UID("cluster-a"), index: 0}
+				pc := newTestPooledConn(t, f, key, 1)
+				f.conns[key] = pc
+
+				if err := pc.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+				if pc.refs != 0 {
+					t.Fatalf("refs = %d, want 0", pc.refs)
+				}
+				if pc.conn.GetState() == connectivity.Shutdown {
+					t.Fatalf("idle-but-cached connection was closed")
+				}
+				if cached, ok := f.conns[key]; !ok || cached != pc {
+					t.Fatalf("connection should remain cached for reuse")
+				}
+			},
+		},
+		{
+			name: "closing a reference to a connection evicted and replaced under it tears down the stale one, not the new one",
+			run: func(t *testing.T) {
+				f := newTestFactory()
+				key := agentKey{clusterUID: types.UID("cluster-a"), index: 0}
+
+				stale := newTestPooledConn(t, f, key, 1)
+				f.conns[key] = stale
+
+				// Simulate New() evicting stale (e.g. "pod_ip_changed") while a
+				// caller still holds a reference to it: evictLocked removes it
+				// from the cache but, since refs > 0, leaves the connection
+				// itself open for that caller to finish using.
+				f.mu.Lock()
+				f.evictLocked(key, "pod_ip_changed")
+				f.mu.Unlock()
+				if stale.conn.GetState() == connectivity.Shutdown {
+					t.Fatalf("evictLocked closed a connection that still had an active reference")
+				}
+
+				// A fresh dial now occupies the same cache key.
+				fresh := newTestPooledConn(t, f, key, 1)
+				f.conns[key] = fresh
+
+				// The caller that was still holding `stale` finally releases it.
+				// Matching on key presence alone would see `fresh` cached under
+				// the same key and wrongly conclude stale is still wanted.
+				if err := stale.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+				if stale.conn.GetState() != connectivity.Shutdown {
+					t.Fatalf("stale connection was leaked instead of being closed")
+				}
+				if fresh.conn.GetState() == connectivity.Shutdown {
+					t.Fatalf("closing the stale reference incorrectly tore down the fresh connection")
+				}
+				if cached, ok := f.conns[key]; !ok || cached != fresh {
+					t.Fatalf("fresh connection should still be cached under key")
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, tc.run)
+	}
+}
+
+func TestDefaultAgentFactoryReset(t *testing.T) {
+	f := newTestFactory()
+	keyA := agentKey{clusterUID: types.UID("cluster-a"), index: 0}
+	keyB := agentKey{clusterUID: types.UID("cluster-b"), index: 0}
+
+	connA := newTestPooledConn(t, f, keyA, 0)
+	connB := newTestPooledConn(t, f, keyB, 0)
+	f.conns[keyA] = connA
+	f.conns[keyB] = connB
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	cluster.UID = types.UID("cluster-a")
+	f.Reset(cluster)
+
+	if _, ok := f.conns[keyA]; ok {
+		t.Fatalf("cluster-a's connection should have been evicted by Reset")
+	}
+	if connA.conn.GetState() != connectivity.Shutdown {
+		t.Fatalf("cluster-a's unreferenced connection should have been closed by Reset")
+	}
+	if _, ok := f.conns[keyB]; !ok {
+		t.Fatalf("cluster-b's connection should be unaffected by resetting cluster-a")
+	}
+	if connB.conn.GetState() == connectivity.Shutdown {
+		t.Fatalf("cluster-b's connection should not have been closed")
+	}
+}
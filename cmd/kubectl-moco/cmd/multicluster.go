@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	multiClusterServiceAccountName = "moco-multicluster-agent"
+	multiClusterRoleName           = "moco-multicluster-agent"
+	multiClusterKubeconfigKey      = "kubeconfig"
+)
+
+var multiClusterSetupOpts struct {
+	namespace      string
+	secretName     string
+	serverOverride string
+}
+
+var multiClusterCmd = &cobra.Command{
+	Use:   "multicluster",
+	Short: "Manage multi-Kubernetes-cluster MySQLCluster topologies",
+}
+
+var multiClusterSetupCmd = &cobra.Command{
+	Use:   "setup CONTEXT",
+	Short: "Prepare a member cluster to host remote MySQLCluster instances",
+	Long: `setup creates a ServiceAccount, a Role/RoleBinding scoped to the
+instances MOCO needs to manage, and a kubeconfig Secret in the member
+cluster named by CONTEXT (an entry in the caller's kubeconfig). The
+resulting secret is meant to be copied into the hub cluster and referenced
+by a MySQLClusterMember's spec.kubeconfigSecretName.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMultiClusterSetup(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	multiClusterSetupCmd.Flags().StringVar(&multiClusterSetupOpts.namespace, "namespace", "moco-system", "namespace to create the ServiceAccount and Secret in")
+	multiClusterSetupCmd.Flags().StringVar(&multiClusterSetupOpts.secretName, "secret-name", "", "name of the kubeconfig Secret to create (defaults to moco-member-<context>)")
+	multiClusterSetupCmd.Flags().StringVar(&multiClusterSetupOpts.serverOverride, "server", "", "override the API server URL recorded in the generated kubeconfig")
+	multiClusterCmd.AddCommand(multiClusterSetupCmd)
+	rootCmd.AddCommand(multiClusterCmd)
+}
+
+func runMultiClusterSetup(ctx context.Context, kubeContext string) error {
+	restConfig, err := newRestConfigForContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("unable to build client config for context %q: %w", kubeContext, err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create clientset: %w", err)
+	}
+
+	ns := multiClusterSetupOpts.namespace
+	if err := ensureNamespace(ctx, clientset, ns); err != nil {
+		return err
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: multiClusterServiceAccountName}}
+	if _, err := clientset.CoreV1().ServiceAccounts(ns).Create(ctx, sa, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create ServiceAccount: %w", err)
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: multiClusterRoleName},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods", "pods/exec", "services", "secrets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"apps"},
+				Resources: []string{"statefulsets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	if _, err := clientset.RbacV1().Roles(ns).Create(ctx, role, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create Role: %w", err)
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: multiClusterRoleName},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: multiClusterRoleName},
+		Subjects: []rbacv1.Subject{
+			{Kind: "ServiceAccount", Namespace: ns, Name: multiClusterServiceAccountName},
+		},
+	}
+	if _, err := clientset.RbacV1().RoleBindings(ns).Create(ctx, binding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create RoleBinding: %w", err)
+	}
+
+	token, err := requestServiceAccountToken(ctx, clientset, ns, multiClusterServiceAccountName)
+	if err != nil {
+		return fmt.Errorf("unable to mint ServiceAccount token: %w", err)
+	}
+
+	server := multiClusterSetupOpts.serverOverride
+	if server == "" {
+		server = restConfig.Host
+	}
+	kubeconfig, err := buildMemberKubeconfig(kubeContext, server, restConfig.CAData, token)
+	if err != nil {
+		return fmt.Errorf("unable to build member kubeconfig: %w", err)
+	}
+
+	secretName := multiClusterSetupOpts.secretName
+	if secretName == "" {
+		secretName = "moco-member-" + kubeContext
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: secretName},
+		Data:       map[string][]byte{multiClusterKubeconfigKey: kubeconfig},
+	}
+	if _, err := clientset.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to create kubeconfig Secret: %w", err)
+		}
+		if _, err := clientset.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update kubeconfig Secret: %w", err)
+		}
+	}
+
+	fmt.Printf("created secret %s/%s in context %q; copy it to the hub cluster and reference it from a MySQLClusterMember\n", ns, secretName, kubeContext)
+	return nil
+}
+
+func ensureNamespace(ctx context.Context, clientset kubernetes.Interface, name string) error {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	_, err := clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create namespace %s: %w", name, err)
+	}
+	return nil
+}
+
+// memberTokenExpirationSeconds is the requested validity of the token baked
+// into the member kubeconfig Secret. The Secret is meant to back ongoing
+// cross-cluster dialing indefinitely, not a single session, so this
+// requests the longest duration TokenRequest allows (most clusters clamp
+// to their service account issuer's configured maximum, but a maximal
+// request avoids silently falling back to the ~1h default).
+const memberTokenExpirationSeconds = 100 * 365 * 24 * 3600 // ~100 years
+
+func requestServiceAccountToken(ctx context.Context, clientset kubernetes.Interface, namespace, name string) ([]byte, error) {
+	expiration := int64(memberTokenExpirationSeconds)
+	tr, err := clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(tr.Status.Token), nil
+}
+
+// newRestConfigForContext builds a *rest.Config for kubeContext using the
+// caller's default kubeconfig loading rules (KUBECONFIG env var, then
+// ~/.kube/config), the same way other kubectl plugins resolve contexts.
+func newRestConfigForContext(kubeContext string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+func buildMemberKubeconfig(contextName, server string, caData []byte, token []byte) ([]byte, error) {
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[contextName] = &clientcmdapi.Cluster{Server: server, CertificateAuthorityData: caData}
+	cfg.AuthInfos[multiClusterServiceAccountName] = &clientcmdapi.AuthInfo{Token: string(token)}
+	cfg.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName, AuthInfo: multiClusterServiceAccountName}
+	cfg.CurrentContext = contextName
+	return clientcmd.Write(*cfg)
+}
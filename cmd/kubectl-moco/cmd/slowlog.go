@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/slowlog"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var slowlogOpts struct {
+	topN int
+	port int
+}
+
+var slowlogCmd = &cobra.Command{
+	Use:   "slowlog CLUSTER",
+	Short: "Show the heaviest slow-query digests across a MySQLCluster's replicas",
+	Long: `slowlog queries the /slowlog endpoint exposed by the slow-log
+sidecar on every pod of CLUSTER and prints the top-N heaviest digests,
+merged and re-sorted across pods.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSlowlog(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	slowlogCmd.Flags().IntVar(&slowlogOpts.topN, "top", 20, "number of digests to display")
+	slowlogCmd.Flags().IntVar(&slowlogOpts.port, "port", 8081, "port the slow-log sidecar serves /slowlog on")
+	rootCmd.AddCommand(slowlogCmd)
+}
+
+func runSlowlog(ctx context.Context, clusterName string) error {
+	pods, err := listClusterPods(ctx, clusterName)
+	if err != nil {
+		return fmt.Errorf("unable to list pods for cluster %s: %w", clusterName, err)
+	}
+
+	var merged []slowlog.DigestStats
+	for _, pod := range pods {
+		stats, err := fetchSlowlog(ctx, pod, slowlogOpts.port)
+		if err != nil {
+			fmt.Printf("warning: unable to fetch slowlog from pod %s: %v\n", pod, err)
+			continue
+		}
+		merged = append(merged, stats...)
+	}
+
+	merged = mergeDigests(merged)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].TotalTime > merged[j].TotalTime })
+	if len(merged) > slowlogOpts.topN {
+		merged = merged[:slowlogOpts.topN]
+	}
+
+	printDigestTable(merged)
+	return nil
+}
+
+// listClusterPods returns the IP of every pod belonging to clusterName in
+// the current kubectl namespace, the same label selector the "mysql"
+// subcommand uses to find the cluster's StatefulSet pods.
+func listClusterPods(ctx context.Context, clusterName string) ([]string, error) {
+	clientset, namespace, err := newClientsetAndNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := fmt.Sprintf("app.kubernetes.io/instance=%s,app.kubernetes.io/name=mysql", clusterName)
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Status.PodIP != "" {
+			ips = append(ips, pod.Status.PodIP)
+		}
+	}
+	return ips, nil
+}
+
+// newClientsetAndNamespace builds a clientset and resolves the active
+// namespace from the caller's kubeconfig, honoring --namespace the same way
+// other kubectl-moco subcommands do.
+func newClientsetAndNamespace() (*kubernetes.Clientset, string, error) {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to build client config: %w", err)
+	}
+	ns, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to resolve namespace: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to create clientset: %w", err)
+	}
+	return clientset, ns, nil
+}
+
+// fetchSlowlog retrieves the digest table from a single pod's sidecar.
+func fetchSlowlog(ctx context.Context, podIP string, port int) ([]slowlog.DigestStats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s:%d/slowlog", podIP, port), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stats []slowlog.DigestStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+	return stats, nil
+}
+
+// mergeDigests combines per-pod entries for the same digest into one row.
+func mergeDigests(in []slowlog.DigestStats) []slowlog.DigestStats {
+	byDigest := make(map[string]*slowlog.DigestStats, len(in))
+	for _, s := range in {
+		s := s
+		existing, ok := byDigest[s.Digest]
+		if !ok {
+			byDigest[s.Digest] = &s
+			continue
+		}
+		existing.Count += s.Count
+		existing.TotalTime += s.TotalTime
+		existing.RowsExamined += s.RowsExamined
+		if s.MaxTime > existing.MaxTime {
+			existing.MaxTime = s.MaxTime
+		}
+		if s.LastSeen.After(existing.LastSeen) {
+			existing.LastSeen = s.LastSeen
+		}
+	}
+
+	out := make([]slowlog.DigestStats, 0, len(byDigest))
+	for _, s := range byDigest {
+		out = append(out, *s)
+	}
+	return out
+}
+
+func printDigestTable(stats []slowlog.DigestStats) {
+	fmt.Printf("%-16s %8s %12s %10s %12s  %s\n", "DIGEST", "COUNT", "TOTAL_TIME", "MAX_TIME", "ROWS_EXAM", "SAMPLE QUERY")
+	for _, s := range stats {
+		fmt.Printf("%-16s %8d %12.3f %10.3f %12d  %s\n", s.Digest, s.Count, s.TotalTime, s.MaxTime, s.RowsExamined, s.SampleQuery)
+	}
+}
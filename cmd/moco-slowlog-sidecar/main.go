@@ -0,0 +1,65 @@
+// Command moco-slowlog-sidecar tails a mysqld slow-query log, parses each
+// entry, exports Prometheus metrics for it, and serves a top-N heaviest
+// digest table over HTTP for `kubectl moco slowlog` to query.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cybozu-go/moco/pkg/slowlog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	logPath := flag.String("slow-log-file", "/var/log/mysql/mysql-slow.log", "path to the mysqld slow-query log")
+	addr := flag.String("addr", ":8081", "address to serve /metrics and /slowlog on")
+	retention := flag.Duration("digest-retention", 24*time.Hour, "how long an idle query digest is kept in the top-N table")
+	topN := flag.Int("top-n", 20, "number of heaviest digests to keep and serve")
+	samplingRateFlag := flag.String("sampling-rate", "1", "fraction in [0,1] of slow-query events to export to metrics/digests")
+	flag.Parse()
+
+	samplingRate, err := slowlog.SamplingRate(*samplingRateFlag)
+	if err != nil {
+		log.Fatalf("invalid --sampling-rate: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reg := prometheus.NewRegistry()
+	metrics := slowlog.NewMetrics(reg)
+	table := slowlog.NewDigestTable(*retention)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.Handle("/slowlog", slowlog.NewHandler(table, *topN))
+
+	srv := &http.Server{Addr: *addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("slowlog http server failed: %v", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	err = slowlog.Follow(ctx, *logPath, samplingRate, func(ev slowlog.Event) {
+		metrics.Observe(ev)
+		table.Add(ev)
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Fatalf("slowlog follow failed: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backupResult is the JSON summary the `moco backup` CLI writes to its
+// container's termination log on exit, win or lose. It is how
+// reflectJobStatus recovers the observed backup metadata that the Job
+// itself, being a black box to the controller, can't otherwise report.
+type backupResult struct {
+	BinlogFilename string  `json:"binlogFilename"`
+	BinlogPosition int64   `json:"binlogPosition"`
+	GTIDSet        string  `json:"gtidSet"`
+	BackupSize     int64   `json:"backupSize"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// MySQLBackupReconciler reconciles a MySQLBackup object.
+type MySQLBackupReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// Reconcile creates a Job that performs the backup described by the
+// MySQLBackup, then watches it to completion and reflects the result in
+// status.
+func (r *MySQLBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("mysqlbackup", req.NamespacedName)
+
+	backup := &mocov1beta2.MySQLBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLBackup: %w", err)
+	}
+
+	if backup.Status.Completed {
+		return ctrl.Result{}, nil
+	}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	clusterName := types.NamespacedName{Namespace: backup.Namespace, Name: backup.Spec.ClusterName}
+	if err := r.Get(ctx, clusterName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			backup.Status.SetCondition(mocov1beta2.BackupCondition{
+				Type:               mocov1beta2.BackupConditionFailed,
+				Status:             corev1.ConditionTrue,
+				Reason:             "ClusterNotFound",
+				Message:            err.Error(),
+				LastTransitionTime: metav1.Now(),
+			})
+			backup.Status.Completed = true
+			return ctrl.Result{}, r.Status().Update(ctx, backup)
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLCluster %s: %w", backup.Spec.ClusterName, err)
+	}
+
+	job := &batchv1.Job{}
+	jobName := types.NamespacedName{Namespace: backup.Namespace, Name: backup.Name}
+	err := r.Get(ctx, jobName, job)
+	switch {
+	case apierrors.IsNotFound(err):
+		job = r.jobForBackup(backup, cluster)
+		if err := ctrl.SetControllerReference(backup, job, r.Scheme); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to set controller reference: %w", err)
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to create backup Job: %w", err)
+		}
+		backup.Status.JobName = job.Name
+		backup.Status.SetCondition(mocov1beta2.BackupCondition{
+			Type:               mocov1beta2.BackupConditionInProgress,
+			Status:             corev1.ConditionTrue,
+			Reason:             "JobCreated",
+			LastTransitionTime: metav1.Now(),
+		})
+		return ctrl.Result{Requeue: true}, r.Status().Update(ctx, backup)
+	case err != nil:
+		return ctrl.Result{}, fmt.Errorf("unable to fetch backup Job: %w", err)
+	}
+
+	return r.reflectJobStatus(ctx, log, backup, job)
+}
+
+// reflectJobStatus updates the MySQLBackup status based on the backup Job's
+// completion state. It sets Status.Completed to true whether the Job failed
+// or succeeded, since callers need a terminal signal either way.
+func (r *MySQLBackupReconciler) reflectJobStatus(ctx context.Context, log logr.Logger, backup *mocov1beta2.MySQLBackup, job *batchv1.Job) (ctrl.Result, error) {
+	for _, cond := range job.Status.Conditions {
+		switch {
+		case cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue:
+			backup.Status.Completed = true
+			backup.Status.SetCondition(mocov1beta2.BackupCondition{
+				Type:               mocov1beta2.BackupConditionInProgress,
+				Status:             corev1.ConditionFalse,
+				Reason:             "JobSucceeded",
+				LastTransitionTime: metav1.Now(),
+			})
+			if err := r.populateBackupMetadata(ctx, backup, job); err != nil {
+				log.Error(err, "failed to read backup result from Job pod", "job", job.Name)
+			}
+			backup.Status.SetCondition(mocov1beta2.BackupCondition{
+				Type:               mocov1beta2.BackupConditionReady,
+				Status:             corev1.ConditionTrue,
+				Reason:             "JobSucceeded",
+				LastTransitionTime: metav1.Now(),
+			})
+			return ctrl.Result{}, r.Status().Update(ctx, backup)
+		case cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue:
+			backup.Status.Completed = true
+			backup.Status.SetCondition(mocov1beta2.BackupCondition{
+				Type:               mocov1beta2.BackupConditionInProgress,
+				Status:             corev1.ConditionFalse,
+				Reason:             "JobFailed",
+				LastTransitionTime: metav1.Now(),
+			})
+			backup.Status.SetCondition(mocov1beta2.BackupCondition{
+				Type:               mocov1beta2.BackupConditionFailed,
+				Status:             corev1.ConditionTrue,
+				Reason:             cond.Reason,
+				Message:            cond.Message,
+				LastTransitionTime: metav1.Now(),
+			})
+			return ctrl.Result{}, r.Status().Update(ctx, backup)
+		}
+	}
+
+	log.V(1).Info("backup Job is still running", "job", job.Name)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// populateBackupMetadata fills in the observed binlog position, size, GTID
+// set and elapsed time recorded by the completed Job's pod. Elapsed falls
+// back to the Job's own start/completion timestamps if the container
+// didn't report one.
+func (r *MySQLBackupReconciler) populateBackupMetadata(ctx context.Context, backup *mocov1beta2.MySQLBackup, job *batchv1.Job) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return fmt.Errorf("unable to list Job pods: %w", err)
+	}
+
+	// A Job's default BackoffLimit allows several Pods to have run before one
+	// succeeds, and List order across them is arbitrary, so only the
+	// container that actually exited 0 may be trusted; a failed attempt's
+	// termination message can be stale or truncated and must not abort the
+	// whole scan, or the succeeded Pod's result right next to it in the list
+	// never gets read.
+	var result *backupResult
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != "backup" || cs.State.Terminated == nil || cs.State.Terminated.ExitCode != 0 {
+				continue
+			}
+			var br backupResult
+			if err := json.Unmarshal([]byte(cs.State.Terminated.Message), &br); err != nil {
+				continue
+			}
+			result = &br
+		}
+	}
+	if result == nil {
+		return fmt.Errorf("no successfully terminated backup container found for job %s", job.Name)
+	}
+
+	backup.Status.BinlogFilename = result.BinlogFilename
+	backup.Status.BinlogPosition = result.BinlogPosition
+	backup.Status.GTIDSet = result.GTIDSet
+	backup.Status.BackupSize = result.BackupSize
+
+	elapsed := time.Duration(result.ElapsedSeconds * float64(time.Second))
+	if elapsed == 0 && job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+		elapsed = job.Status.CompletionTime.Sub(job.Status.StartTime.Time)
+	}
+	backup.Status.Elapsed = &metav1.Duration{Duration: elapsed}
+	return nil
+}
+
+// jobForBackup builds the Job that invokes the existing `moco backup` CLI
+// logic against the target cluster's primary instance.
+func (r *MySQLBackupReconciler) jobForBackup(backup *mocov1beta2.MySQLBackup, cluster *mocov1beta2.MySQLCluster) *batchv1.Job {
+	bucket := ""
+	serviceAccount := ""
+	if cfg := backup.Spec.JobConfig; cfg != nil {
+		bucket = cfg.BucketName
+		serviceAccount = cfg.ServiceAccountName
+	}
+
+	job := &batchv1.Job{}
+	job.Namespace = backup.Namespace
+	job.Name = backup.Name
+	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	job.Spec.Template.Spec.ServiceAccountName = serviceAccount
+	job.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:  "backup",
+			Image: "ghcr.io/cybozu-go/moco:latest",
+			Args: []string{
+				"backup",
+				"--cluster-name", cluster.Name,
+				"--bucket-name", bucket,
+			},
+			// `moco backup` writes a JSON backupResult summary here on
+			// exit; reflectJobStatus reads it back from the Pod status to
+			// fill in MySQLBackupStatus's observed metadata.
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+	}
+	return job
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MySQLBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mocov1beta2.MySQLBackup{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MySQLBackupScheduleReconciler reconciles a MySQLBackupSchedule object.
+type MySQLBackupScheduleReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackupschedules,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlbackups,verbs=get;list;watch;create;delete
+
+// Reconcile creates a new MySQLBackup whenever the schedule's Cron
+// expression is due, and prunes old MySQLBackups beyond the configured
+// history limits.
+func (r *MySQLBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("mysqlbackupschedule", req.NamespacedName)
+
+	schedule := &mocov1beta2.MySQLBackupSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLBackupSchedule: %w", err)
+	}
+
+	sched, err := cron.ParseStandard(schedule.Spec.Schedule)
+	if err != nil {
+		log.Error(err, "invalid schedule", "schedule", schedule.Spec.Schedule)
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+
+	if schedule.Spec.Suspend {
+		// Nothing to schedule while suspended; just poll occasionally in
+		// case Suspend is cleared. Using sched.Next(last) here would anchor
+		// on a long-past LastScheduleTime (or its zero value) and produce a
+		// negative RequeueAfter, which controller-runtime treats as
+		// "requeue immediately" -- a busy loop.
+		return ctrl.Result{RequeueAfter: sched.Next(now).Sub(now)}, nil
+	}
+
+	var last time.Time
+	if schedule.Status.LastScheduleTime != nil {
+		last = schedule.Status.LastScheduleTime.Time
+	}
+	next := sched.Next(last)
+
+	if !next.After(now) {
+		backup := &mocov1beta2.MySQLBackup{}
+		backup.Namespace = schedule.Namespace
+		backup.Name = fmt.Sprintf("%s-%d", schedule.Name, now.Unix())
+		backup.Spec = schedule.Spec.BackupTemplate
+		backup.Spec.ClusterName = schedule.Spec.ClusterName
+		if err := ctrl.SetControllerReference(schedule, backup, r.Scheme); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to set controller reference: %w", err)
+		}
+		if err := r.Create(ctx, backup); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to create MySQLBackup: %w", err)
+		}
+
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: now}
+		schedule.Status.LastBackupName = backup.Name
+		if err := r.Status().Update(ctx, schedule); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to update MySQLBackupSchedule status: %w", err)
+		}
+
+		if err := r.pruneOldBackups(ctx, schedule); err != nil {
+			log.Error(err, "failed to prune old backups")
+		}
+
+		next = sched.Next(now)
+	}
+
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// pruneOldBackups deletes completed MySQLBackups beyond the configured
+// SuccessfulBackupsHistoryLimit/FailedBackupsHistoryLimit, keeping the most
+// recent ones.
+func (r *MySQLBackupScheduleReconciler) pruneOldBackups(ctx context.Context, schedule *mocov1beta2.MySQLBackupSchedule) error {
+	list := &mocov1beta2.MySQLBackupList{}
+	if err := r.List(ctx, list, client.InNamespace(schedule.Namespace)); err != nil {
+		return fmt.Errorf("unable to list MySQLBackups: %w", err)
+	}
+
+	var succeeded, failed []mocov1beta2.MySQLBackup
+	for _, b := range list.Items {
+		if !metav1.IsControlledBy(&b, schedule) {
+			continue
+		}
+		switch {
+		case b.Status.GetCondition(mocov1beta2.BackupConditionReady) != nil:
+			succeeded = append(succeeded, b)
+		case b.Status.GetCondition(mocov1beta2.BackupConditionFailed) != nil:
+			failed = append(failed, b)
+		}
+	}
+
+	successLimit := schedule.Spec.SuccessfulBackupsHistoryLimit
+	if successLimit == 0 {
+		successLimit = 3
+	}
+	failLimit := schedule.Spec.FailedBackupsHistoryLimit
+	if failLimit == 0 {
+		failLimit = 1
+	}
+
+	if err := r.deleteOldest(ctx, succeeded, int(successLimit)); err != nil {
+		return err
+	}
+	return r.deleteOldest(ctx, failed, int(failLimit))
+}
+
+func (r *MySQLBackupScheduleReconciler) deleteOldest(ctx context.Context, backups []mocov1beta2.MySQLBackup, limit int) error {
+	if len(backups) <= limit {
+		return nil
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreationTimestamp.Before(&backups[j].CreationTimestamp)
+	})
+	for _, b := range backups[:len(backups)-limit] {
+		b := b
+		if err := r.Delete(ctx, &b); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete MySQLBackup %s: %w", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MySQLBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mocov1beta2.MySQLBackupSchedule{}).
+		Owns(&mocov1beta2.MySQLBackup{}).
+		Complete(r)
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// mysqlClusterMemberPollInterval is how often a MySQLClusterMember's
+// reachability is re-checked.
+const mysqlClusterMemberPollInterval = 30 * time.Second
+
+// mysqlClusterMemberDialTimeout bounds a single reachability check so that a
+// remote cluster that's gone dark doesn't stall the reconcile loop.
+const mysqlClusterMemberDialTimeout = 5 * time.Second
+
+// MySQLClusterMemberReconciler reconciles a MySQLClusterMember object. It
+// periodically resolves each declared instance index through
+// MySQLClusterMemberResolver and records whether at least one of them could
+// be dialed, and keeps this cluster's replication-source secret in sync with
+// the peer's user secret so configureIntermediatePrimaryOp never has to
+// reach across clusters itself.
+type MySQLClusterMemberReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Resolver *MySQLClusterMemberResolver
+}
+
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclustermembers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclustermembers/status,verbs=get;update;patch
+
+// Reconcile dials the instance indices declared by the MySQLClusterMember in
+// order, stopping at the first one that answers, and records whether any did.
+func (r *MySQLClusterMemberReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("mysqlclustermember", req.NamespacedName)
+
+	member := &mocov1beta2.MySQLClusterMember{}
+	if err := r.Get(ctx, req.NamespacedName, member); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLClusterMember: %w", err)
+	}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	clusterName := types.NamespacedName{Namespace: member.Namespace, Name: member.Spec.ClusterName}
+	if err := r.Get(ctx, clusterName, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLCluster %s: %w", member.Spec.ClusterName, err)
+	}
+
+	reachable := false
+	for _, index := range member.Spec.Indices {
+		if r.dialIndex(ctx, log, cluster, index) {
+			reachable = true
+			break
+		}
+	}
+
+	if reachable {
+		if err := r.mirrorReplicationSource(ctx, member, cluster); err != nil {
+			log.Error(err, "failed to mirror replication-source secret from peer")
+		}
+	}
+
+	now := metav1.Now()
+	member.Status.Reachable = reachable
+	member.Status.LastSeenTime = &now
+	if err := r.Status().Update(ctx, member); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update MySQLClusterMember status: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: mysqlClusterMemberPollInterval}, nil
+}
+
+// mirrorReplicationSource populates member.Spec.ReplicationSourceSecretName
+// from member.Spec.PeerUserSecretName, provided both are set and the
+// cluster's current primary is one of the instances this member hosts. It
+// is a no-op otherwise, e.g. while the primary still lives locally or in a
+// different MySQLClusterMember.
+func (r *MySQLClusterMemberReconciler) mirrorReplicationSource(ctx context.Context, member *mocov1beta2.MySQLClusterMember, cluster *mocov1beta2.MySQLCluster) error {
+	if member.Spec.ReplicationSourceSecretName == "" || member.Spec.PeerUserSecretName == "" {
+		return nil
+	}
+
+	primaryIndex := cluster.Status.CurrentPrimaryIndex
+	hostsPrimary := false
+	for _, index := range member.Spec.Indices {
+		if index == primaryIndex {
+			hostsPrimary = true
+			break
+		}
+	}
+	if !hostsPrimary {
+		return nil
+	}
+
+	addr, found, err := r.Resolver.ResolveMember(ctx, cluster, primaryIndex)
+	if err != nil {
+		return fmt.Errorf("unable to resolve primary instance address: %w", err)
+	}
+	if !found {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("unable to parse primary instance address %q: %w", addr, err)
+	}
+
+	peerSecret := &corev1.Secret{}
+	peerSecretName := client.ObjectKey{Namespace: member.Namespace, Name: member.Spec.PeerUserSecretName}
+	if err := r.Get(ctx, peerSecretName, peerSecret); err != nil {
+		return fmt.Errorf("unable to fetch peer user secret %s: %w", peerSecretName, err)
+	}
+
+	primaryUser := member.Spec.PrimaryUser
+	if primaryUser == "" {
+		primaryUser = "root"
+	}
+	primaryPort := member.Spec.PrimaryMySQLPort
+	if primaryPort == 0 {
+		primaryPort = 3306
+	}
+
+	secret := &corev1.Secret{}
+	secret.Namespace = member.Namespace
+	secret.Name = member.Spec.ReplicationSourceSecretName
+	_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Data = MirrorReplicationSourceSecret(peerSecret, host, primaryPort, primaryUser)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to apply replication-source secret %s: %w", member.Spec.ReplicationSourceSecretName, err)
+	}
+	return nil
+}
+
+// dialIndex resolves index through r.Resolver and reports whether a TCP
+// connection to it succeeds. A plain dial, rather than a full agent RPC, is
+// enough to answer "is the remote cluster reachable at all" without needing
+// a pooled, authenticated AgentConn just to poll status.
+func (r *MySQLClusterMemberReconciler) dialIndex(ctx context.Context, log logr.Logger, cluster *mocov1beta2.MySQLCluster, index int) bool {
+	ctx, cancel := context.WithTimeout(ctx, mysqlClusterMemberDialTimeout)
+	defer cancel()
+
+	addr, found, err := r.Resolver.ResolveMember(ctx, cluster, index)
+	if err != nil {
+		log.V(1).Info("unable to resolve remote member address", "index", index, "error", err.Error())
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		log.V(1).Info("remote member instance unreachable", "index", index, "addr", addr, "error", err.Error())
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MySQLClusterMemberReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mocov1beta2.MySQLClusterMember{}).
+		Complete(r)
+}
+
+// MirrorReplicationSourceSecret builds the replication-source secret data
+// (the keys configureIntermediatePrimaryOp reads: PRIMARY_HOST, PRIMARY_PORT,
+// PRIMARY_USER, PRIMARY_PASSWORD) for an instance hosted in a peer cluster,
+// taking the password from that peer's own user Secret rather than requiring
+// it to be copied by hand. It's exported so mirrorReplicationSource and its
+// tests build the same secret the same way.
+func MirrorReplicationSourceSecret(peerUserSecret *corev1.Secret, primaryHost string, primaryPort int, primaryUser string) map[string][]byte {
+	return map[string][]byte{
+		"PRIMARY_HOST":     []byte(primaryHost),
+		"PRIMARY_PORT":     []byte(strconv.Itoa(primaryPort)),
+		"PRIMARY_USER":     []byte(primaryUser),
+		"PRIMARY_PASSWORD": peerUserSecret.Data["PASSWORD"],
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/clustering"
+	"github.com/cybozu-go/moco/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MySQLClusterMemberResolver implements clustering.MemberResolver by
+// consulting the MySQLClusterMember objects in the hub cluster: for an
+// instance index listed in some member's Spec.Indices, it resolves the
+// dial address according to the member's PodAddressMode instead of through
+// the local dbop.Resolver. Mesh and LoadBalancer both need the kubeconfig
+// in Spec.KubeconfigSecretName to talk to the remote cluster's API server;
+// ExternalName only ever looks at the hub cluster.
+type MySQLClusterMemberResolver struct {
+	client.Client
+}
+
+var _ clustering.MemberResolver = &MySQLClusterMemberResolver{}
+
+// ResolveMember implements clustering.MemberResolver.
+func (r *MySQLClusterMemberResolver) ResolveMember(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (string, bool, error) {
+	member, err := r.memberForIndex(ctx, cluster, index)
+	if err != nil {
+		return "", false, err
+	}
+	if member == nil {
+		return "", false, nil
+	}
+
+	switch member.Spec.PodAddressMode {
+	case mocov1beta2.PodAddressModeExternalName:
+		addr, err := r.resolveExternalName(ctx, member, cluster, index)
+		return addr, true, err
+	case mocov1beta2.PodAddressModeLoadBalancer:
+		addr, err := r.resolveLoadBalancer(ctx, member, cluster, index)
+		return addr, true, err
+	default:
+		addr, err := r.resolveMesh(ctx, member, cluster, index)
+		return addr, true, err
+	}
+}
+
+// resolveMesh dials the pod directly through a flat cross-cluster network,
+// looking up its current IP from the remote cluster's API server.
+func (r *MySQLClusterMemberResolver) resolveMesh(ctx context.Context, member *mocov1beta2.MySQLClusterMember, cluster *mocov1beta2.MySQLCluster, index int) (string, error) {
+	remoteClient, err := r.remoteClientset(ctx, member)
+	if err != nil {
+		return "", fmt.Errorf("unable to build client for member %s: %w", member.Name, err)
+	}
+
+	podName := cluster.PodName(index)
+	pod, err := remoteClient.CoreV1().Pods(member.Namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to find pod %s in remote cluster for member %s: %w", podName, member.Name, err)
+	}
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s in remote cluster for member %s has no IP yet", podName, member.Name)
+	}
+
+	return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(constants.AgentPort)), nil
+}
+
+// resolveExternalName dials through a Service of type ExternalName, named
+// after the instance's pod, that lives alongside the MySQLClusterMember in
+// the hub cluster and points at the remote cluster's ingress. This avoids
+// needing a flat network between clusters: whatever fronts the remote
+// cluster's ingress is responsible for routing the hostname to the right
+// instance.
+func (r *MySQLClusterMemberResolver) resolveExternalName(ctx context.Context, member *mocov1beta2.MySQLClusterMember, cluster *mocov1beta2.MySQLCluster, index int) (string, error) {
+	svcName := cluster.PodName(index)
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: member.Namespace, Name: svcName}, svc); err != nil {
+		return "", fmt.Errorf("unable to fetch ExternalName service %s for member %s: %w", svcName, member.Name, err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeExternalName || svc.Spec.ExternalName == "" {
+		return "", fmt.Errorf("service %s for member %s is not a populated ExternalName service", svcName, member.Name)
+	}
+
+	return net.JoinHostPort(svc.Spec.ExternalName, strconv.Itoa(constants.AgentPort)), nil
+}
+
+// resolveLoadBalancer dials through a Service of type LoadBalancer, named
+// after the instance's pod, that the remote cluster exposes for it. The
+// Service itself is looked up in the remote cluster, not the hub.
+func (r *MySQLClusterMemberResolver) resolveLoadBalancer(ctx context.Context, member *mocov1beta2.MySQLClusterMember, cluster *mocov1beta2.MySQLCluster, index int) (string, error) {
+	remoteClient, err := r.remoteClientset(ctx, member)
+	if err != nil {
+		return "", fmt.Errorf("unable to build client for member %s: %w", member.Name, err)
+	}
+
+	svcName := cluster.PodName(index)
+	svc, err := remoteClient.CoreV1().Services(member.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch LoadBalancer service %s in remote cluster for member %s: %w", svcName, member.Name, err)
+	}
+	ingress := svc.Status.LoadBalancer.Ingress
+	if len(ingress) == 0 {
+		return "", fmt.Errorf("service %s in remote cluster for member %s has no LoadBalancer ingress yet", svcName, member.Name)
+	}
+
+	host := ingress[0].IP
+	if host == "" {
+		host = ingress[0].Hostname
+	}
+	return net.JoinHostPort(host, strconv.Itoa(constants.AgentPort)), nil
+}
+
+// memberForIndex returns the MySQLClusterMember that declares index as one
+// of its Indices, or nil if index is hosted locally.
+func (r *MySQLClusterMemberResolver) memberForIndex(ctx context.Context, cluster *mocov1beta2.MySQLCluster, index int) (*mocov1beta2.MySQLClusterMember, error) {
+	list := &mocov1beta2.MySQLClusterMemberList{}
+	if err := r.List(ctx, list, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list MySQLClusterMembers: %w", err)
+	}
+	for i := range list.Items {
+		member := &list.Items[i]
+		if member.Spec.ClusterName != cluster.Name {
+			continue
+		}
+		for _, idx := range member.Spec.Indices {
+			if idx == index {
+				return member, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// remoteClientset builds a clientset for the Kubernetes cluster referenced
+// by member, using the kubeconfig stored in its KubeconfigSecretName.
+func (r *MySQLClusterMemberResolver) remoteClientset(ctx context.Context, member *mocov1beta2.MySQLClusterMember) (kubernetes.Interface, error) {
+	secret := &corev1.Secret{}
+	secretName := client.ObjectKey{Namespace: member.Namespace, Name: member.Spec.KubeconfigSecretName}
+	if err := r.Get(ctx, secretName, secret); err != nil {
+		return nil, fmt.Errorf("unable to fetch kubeconfig secret %s: %w", secretName, err)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(secret.Data["kubeconfig"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse kubeconfig: %w", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	"github.com/cybozu-go/moco/clustering"
+	"github.com/cybozu-go/moco/pkg/agentproto"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MySQLDataScriptReconciler reconciles a MySQLDataScript object. It runs the
+// script in-process inside the moco-agent sidecar over the same gRPC
+// channel used to probe instance health, via the pkg/agentproto ExecSQL
+// RPC, rather than requiring the caller to wire their own Job.
+type MySQLDataScriptReconciler struct {
+	client.Client
+	Log          logr.Logger
+	AgentFactory clustering.AgentFactory
+}
+
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqldatascripts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqldatascripts/status,verbs=get;update;patch
+
+// Reconcile executes the script against every instance selected by
+// Spec.Target, recording a per-instance result in status.
+func (r *MySQLDataScriptReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("mysqldatascript", req.NamespacedName)
+
+	script := &mocov1beta2.MySQLDataScript{}
+	if err := r.Get(ctx, req.NamespacedName, script); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLDataScript: %w", err)
+	}
+
+	if script.Status.Completed {
+		return ctrl.Result{}, nil
+	}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	clusterName := types.NamespacedName{Namespace: script.Namespace, Name: script.Spec.ClusterName}
+	if err := r.Get(ctx, clusterName, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to fetch MySQLCluster %s: %w", script.Spec.ClusterName, err)
+	}
+
+	indices, err := r.targetIndices(script, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	results := make([]mocov1beta2.DataScriptStatementResult, 0, len(indices))
+	allSucceeded := true
+	for _, idx := range indices {
+		result := r.runOnInstance(ctx, log, script, cluster, idx)
+		if !result.Succeeded && !result.Skipped {
+			allSucceeded = false
+		}
+		results = append(results, result)
+		if !result.Succeeded && !result.Skipped && script.Spec.AbortOnError {
+			break
+		}
+	}
+
+	script.Status.Results = results
+	script.Status.Completed = true
+	reason, status := "Succeeded", corev1.ConditionTrue
+	condType := mocov1beta2.DataScriptConditionReady
+	if !allSucceeded {
+		condType, reason, status = mocov1beta2.DataScriptConditionFailed, "StatementFailed", corev1.ConditionTrue
+	}
+	script.Status.SetCondition(mocov1beta2.DataScriptCondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+	})
+
+	return ctrl.Result{}, r.Status().Update(ctx, script)
+}
+
+// targetIndices resolves Spec.Target into the concrete instance indices to run against.
+func (r *MySQLDataScriptReconciler) targetIndices(script *mocov1beta2.MySQLDataScript, cluster *mocov1beta2.MySQLCluster) ([]int, error) {
+	switch script.Spec.Target {
+	case mocov1beta2.DataScriptTargetAllReplicas:
+		indices := make([]int, 0, cluster.Spec.Replicas)
+		for i := 0; i < int(cluster.Spec.Replicas); i++ {
+			if i != cluster.Status.CurrentPrimaryIndex {
+				indices = append(indices, i)
+			}
+		}
+		return indices, nil
+	case mocov1beta2.DataScriptTargetIndex:
+		if script.Spec.Index == nil {
+			return nil, fmt.Errorf("spec.index must be set when spec.target is %q", mocov1beta2.DataScriptTargetIndex)
+		}
+		return []int{*script.Spec.Index}, nil
+	default:
+		return []int{cluster.Status.CurrentPrimaryIndex}, nil
+	}
+}
+
+// runOnInstance opens (or reuses) an agent connection for the given
+// instance, optionally checks the precondition query, and streams the
+// script to the sidecar for execution.
+func (r *MySQLDataScriptReconciler) runOnInstance(ctx context.Context, log logr.Logger, script *mocov1beta2.MySQLDataScript, cluster *mocov1beta2.MySQLCluster, index int) mocov1beta2.DataScriptStatementResult {
+	result := mocov1beta2.DataScriptStatementResult{Index: index}
+
+	timeout := 30 * time.Second
+	if script.Spec.Timeout != nil {
+		timeout = script.Spec.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := r.AgentFactory.New(ctx, cluster, index)
+	if err != nil {
+		result.Error = fmt.Errorf("unable to connect to agent: %w", err).Error()
+		return result
+	}
+	defer conn.Close()
+
+	stream, err := agentproto.ExecSQL(ctx, conn.RawConn(), &agentproto.ExecSQLRequest{
+		Script:            script.Spec.Script,
+		PreconditionQuery: script.Spec.PreconditionQuery,
+		DryRun:            script.Spec.DryRun,
+	})
+	if err != nil {
+		result.Error = fmt.Errorf("unable to start ExecSQL stream: %w", err).Error()
+		return result
+	}
+
+	start := time.Now()
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if resp.Skipped {
+			result.Skipped = true
+			return result
+		}
+		if resp.Error != "" {
+			result.Error = resp.Error
+			return result
+		}
+		result.RowsAffected += resp.RowsAffected
+	}
+
+	log.V(1).Info("executed data script", "instance", index, "rowsAffected", result.RowsAffected)
+	result.Succeeded = true
+	result.ExecutionTime = &metav1.Duration{Duration: time.Since(start)}
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MySQLDataScriptReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mocov1beta2.MySQLDataScript{}).
+		Complete(r)
+}
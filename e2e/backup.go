@@ -0,0 +1,21 @@
+package e2e
+
+import (
+	"encoding/json"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+)
+
+// getBackup fetches the named MySQLBackup the same way getCluster fetches a
+// MySQLCluster: via `kubectl get -o json`, unmarshaled into the typed object.
+func getBackup(namespace, name string) (*mocov1beta2.MySQLBackup, error) {
+	out, err := kubectl(nil, "-n", namespace, "get", "mysqlbackup", name, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	backup := &mocov1beta2.MySQLBackup{}
+	if err := json.Unmarshal(out, backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	mocov1beta1 "github.com/cybozu-go/moco/api/v1beta1"
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/common/expfmt"
@@ -20,6 +21,9 @@ import (
 //go:embed testdata/single.yaml
 var singleYAML string
 
+//go:embed testdata/single-backup.yaml
+var backupYAML string
+
 var _ = Context("lifecycle", func() {
 	It("should construct a single-instance cluster", func() {
 		kubectlSafe(fillTemplate(singleYAML), "apply", "-f", "-")
@@ -116,6 +120,24 @@ var _ = Context("lifecycle", func() {
 		Expect(m.GetGauge().GetValue()).To(BeNumerically("==", 1))
 	})
 
+	It("should perform an on-demand backup", func() {
+		kubectlSafe(fillTemplate(backupYAML), "apply", "-f", "-")
+		Eventually(func() error {
+			backup, err := getBackup("foo", "single-backup")
+			if err != nil {
+				return err
+			}
+			if !backup.Status.Completed {
+				return errors.New("backup is not completed")
+			}
+			cond := backup.Status.GetCondition(mocov1beta2.BackupConditionReady)
+			if cond == nil || cond.Status != corev1.ConditionTrue {
+				return fmt.Errorf("backup did not become ready: %+v", backup.Status.Conditions)
+			}
+			return nil
+		}).Should(Succeed())
+	})
+
 	It("should collect generated resources after deleting MySQLCluster", func() {
 		kubectlSafe(nil, "-n", "foo", "delete", "mysqlcluster", "single")
 		Eventually(func() error {
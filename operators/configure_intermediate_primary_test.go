@@ -6,6 +6,7 @@ import (
 
 	"github.com/cybozu-go/moco"
 	"github.com/cybozu-go/moco/accessor"
+	"github.com/cybozu-go/moco/controllers"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -90,4 +91,56 @@ var _ = Describe("Configure intermediate primary operator", func() {
 		Expect(replicaStatus.SlaveIORunning).Should(Equal(moco.ReplicaRunConnect))
 		Expect(replicaStatus.SlaveSQLRunning).Should(Equal(moco.ReplicaRunConnect))
 	})
+
+	It("should configure replication against a peer whose credentials are auto-populated from its user secret, as for a cross-cluster MySQLClusterMember", func() {
+		_, infra, cluster := getAccessorInfraCluster()
+		source := "replication-source"
+		cluster.Spec.ReplicationSourceSecretName = &source
+
+		// In a multi-cluster topology the primary's user secret lives in the
+		// peer cluster; controllers.MirrorReplicationSourceSecret is what the
+		// MySQLClusterMember controller uses to mirror it into this
+		// cluster's replication-source secret, so that
+		// configureIntermediatePrimaryOp never needs to reach across
+		// clusters itself.
+		peerUserSecret := corev1.Secret{}
+		peerUserSecret.Namespace = namespace
+		peerUserSecret.Name = "peer-user-secret"
+		_, err := ctrl.CreateOrUpdate(ctx, k8sClient, &peerUserSecret, func() error {
+			peerUserSecret.Data = map[string][]byte{
+				"PASSWORD": []byte(password),
+			}
+			return nil
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		secret := corev1.Secret{}
+		secret.Namespace = namespace
+		secret.Name = source
+		_, err = ctrl.CreateOrUpdate(ctx, k8sClient, &secret, func() error {
+			secret.Data = controllers.MirrorReplicationSourceSecret(&peerUserSecret, mysqldName2, mysqldPort2, "root")
+			return nil
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		op := configureIntermediatePrimaryOp{
+			Index: 0,
+			Options: &accessor.IntermediatePrimaryOptions{
+				PrimaryHost:     mysqldName2,
+				PrimaryUser:     "root",
+				PrimaryPassword: password,
+				PrimaryPort:     mysqldPort2,
+			},
+		}
+
+		err = op.Run(ctx, infra, &cluster, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		status := accessor.GetMySQLClusterStatus(ctx, logger, infra, &cluster)
+		replicaStatus := status.InstanceStatus[0].ReplicaStatus
+		Expect(replicaStatus).ShouldNot(BeNil())
+		Expect(replicaStatus.MasterHost).Should(Equal(mysqldName2))
+		Expect(replicaStatus.SlaveIORunning).Should(Equal(moco.ReplicaRunConnect))
+		Expect(replicaStatus.SlaveSQLRunning).Should(Equal(moco.ReplicaRunConnect))
+	})
 })
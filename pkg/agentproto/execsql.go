@@ -0,0 +1,88 @@
+// Package agentproto defines the client side of the ExecSQL RPC used by
+// MySQLDataScript. ExecSQL is new: it is not part of the agent.AgentClient
+// generated from github.com/cybozu-go/moco-agent/proto, and that package
+// lives in a separate repository/module this series cannot modify. Rather
+// than block on vendoring a moco-agent bump, ExecSQL is wired up here as a
+// hand-written gRPC client bound to the same *grpc.ClientConn
+// AgentFactory already dials, using a JSON codec so the wire format can be
+// defined without a protoc step. The corresponding sidecar-side handler
+// must be added to moco-agent registering the same method name and codec.
+package agentproto
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// execSQLMethod is the fully-qualified gRPC method name the moco-agent
+// sidecar must register its ExecSQL handler under.
+const execSQLMethod = "/moco.agent.Agent/ExecSQL"
+
+// jsonCodecName is the grpc.CallContentSubtype used for this RPC.
+const jsonCodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec implements grpc/encoding.Codec with encoding/json, so ExecSQL's
+// request/response types need no .proto/protoc-gen-go step.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return jsonCodecName }
+
+// ExecSQLRequest is the script execution request sent to moco-agent.
+type ExecSQLRequest struct {
+	Script            string `json:"script"`
+	PreconditionQuery string `json:"preconditionQuery,omitempty"`
+	DryRun            bool   `json:"dryRun,omitempty"`
+}
+
+// ExecSQLResponse reports the outcome of one statement within the script,
+// or a Skipped/Error terminal result.
+type ExecSQLResponse struct {
+	RowsAffected int64  `json:"rowsAffected,omitempty"`
+	Skipped      bool   `json:"skipped,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ExecSQLClient streams per-statement ExecSQLResponses for a single script run.
+type ExecSQLClient interface {
+	// Recv returns the next response, or io.EOF once the agent has closed the stream.
+	Recv() (*ExecSQLResponse, error)
+}
+
+type execSQLClient struct {
+	stream grpc.ClientStream
+}
+
+func (c *execSQLClient) Recv() (*ExecSQLResponse, error) {
+	resp := new(ExecSQLResponse)
+	if err := c.stream.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ExecSQL opens the ExecSQL RPC on conn and streams req to the agent,
+// returning a client to read per-statement responses from.
+func ExecSQL(ctx context.Context, conn *grpc.ClientConn, req *ExecSQLRequest) (ExecSQLClient, error) {
+	desc := &grpc.StreamDesc{StreamName: "ExecSQL", ServerStreams: true}
+	stream, err := conn.NewStream(ctx, desc, execSQLMethod, grpc.CallContentSubtype(jsonCodecName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open ExecSQL stream: %w", err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, fmt.Errorf("unable to send ExecSQL request: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("unable to close ExecSQL send side: %w", err)
+	}
+	return &execSQLClient{stream: stream}, nil
+}
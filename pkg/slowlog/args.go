@@ -0,0 +1,29 @@
+package slowlog
+
+import (
+	"strconv"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+)
+
+// SidecarArgs converts a SlowQueryConfig into the moco-slowlog-sidecar
+// container args that reproduce it: --digest-retention, --top-n and
+// --sampling-rate. It's the integration point the MySQLCluster controller's
+// StatefulSet builder should call when assembling the slow-log sidecar
+// container; cfg.LongQueryTime is not a sidecar flag (the sidecar only
+// reads the log mysqld already writes) and instead must keep going through
+// the existing my.cnf/SET GLOBAL mechanism the MySQLCluster controller uses
+// for other system variables.
+func SidecarArgs(cfg mocov1beta2.SlowQueryConfig) []string {
+	args := []string{}
+	if cfg.DigestRetention != "" {
+		args = append(args, "--digest-retention", cfg.DigestRetention)
+	}
+	if cfg.TopN > 0 {
+		args = append(args, "--top-n", strconv.Itoa(cfg.TopN))
+	}
+	if cfg.SamplingRate != "" {
+		args = append(args, "--sampling-rate", cfg.SamplingRate)
+	}
+	return args
+}
@@ -0,0 +1,81 @@
+package slowlog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DigestStats aggregates the events seen for a single query digest.
+type DigestStats struct {
+	Digest       string    `json:"digest"`
+	SampleQuery  string    `json:"sampleQuery"`
+	Count        int64     `json:"count"`
+	TotalTime    float64   `json:"totalQueryTime"`
+	MaxTime      float64   `json:"maxQueryTime"`
+	RowsExamined int64     `json:"totalRowsExamined"`
+	LastSeen     time.Time `json:"lastSeen"`
+}
+
+// DigestTable keeps running totals per query digest and evicts entries that
+// have not been seen within retention.
+type DigestTable struct {
+	mu        sync.Mutex
+	retention time.Duration
+	stats     map[string]*DigestStats
+}
+
+// NewDigestTable returns a DigestTable that forgets digests not seen for retention.
+func NewDigestTable(retention time.Duration) *DigestTable {
+	return &DigestTable{retention: retention, stats: make(map[string]*DigestStats)}
+}
+
+// Add folds ev into the table, evicting stale digests first.
+func (t *DigestTable) Add(ev Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.evictLocked(ev.Time)
+
+	s, ok := t.stats[ev.Digest]
+	if !ok {
+		s = &DigestStats{Digest: ev.Digest, SampleQuery: ev.Query}
+		t.stats[ev.Digest] = s
+	}
+	s.Count++
+	s.TotalTime += ev.QueryTime
+	if ev.QueryTime > s.MaxTime {
+		s.MaxTime = ev.QueryTime
+	}
+	s.RowsExamined += ev.RowsExamined
+	s.LastSeen = ev.Time
+}
+
+func (t *DigestTable) evictLocked(now time.Time) {
+	if t.retention <= 0 || now.IsZero() {
+		return
+	}
+	for digest, s := range t.stats {
+		if now.Sub(s.LastSeen) > t.retention {
+			delete(t.stats, digest)
+		}
+	}
+}
+
+// TopN returns up to n digests ordered by descending total query time.
+func (t *DigestTable) TopN(n int) []DigestStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	all := make([]DigestStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		all = append(all, *s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].TotalTime > all[j].TotalTime
+	})
+	if n >= 0 && n < len(all) {
+		all = all[:n]
+	}
+	return all
+}
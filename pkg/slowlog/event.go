@@ -0,0 +1,22 @@
+// Package slowlog parses MySQL slow-query log entries into structured
+// events and exposes them as Prometheus metrics and a top-N digest table,
+// so that slow-log data collected by the slow-log sidecar is no longer
+// limited to raw text in container stdout.
+package slowlog
+
+import "time"
+
+// Event is a single parsed slow-query log entry.
+type Event struct {
+	Time         time.Time
+	User         string
+	Host         string
+	QueryTime    float64
+	LockTime     float64
+	RowsExamined int64
+	RowsSent     int64
+	Query        string
+	// Digest is a hash of Query with literals normalized away, used to
+	// group structurally identical queries together.
+	Digest string
+}
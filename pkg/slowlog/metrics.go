@@ -0,0 +1,35 @@
+package slowlog
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the Prometheus collectors exported by the slow-log sidecar.
+type Metrics struct {
+	QueryTime    prometheus.Histogram
+	RowsExamined prometheus.Histogram
+}
+
+// NewMetrics creates Metrics and registers them with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		QueryTime: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "moco",
+			Name:      "slow_query_time_seconds",
+			Help:      "Observed Query_time of slow-query log entries.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+		}),
+		RowsExamined: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "moco",
+			Name:      "slow_query_rows_examined",
+			Help:      "Observed Rows_examined of slow-query log entries.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 16),
+		}),
+	}
+	reg.MustRegister(m.QueryTime, m.RowsExamined)
+	return m
+}
+
+// Observe records ev in the histograms.
+func (m *Metrics) Observe(ev Event) {
+	m.QueryTime.Observe(ev.QueryTime)
+	m.RowsExamined.Observe(float64(ev.RowsExamined))
+}
@@ -0,0 +1,138 @@
+package slowlog
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeRe       = regexp.MustCompile(`^# Time: (.+)$`)
+	userHostRe   = regexp.MustCompile(`^# User@Host:\s+(\S+)\s+@\s+(\S*)\s*\[`)
+	queryStatsRe = regexp.MustCompile(`^# Query_time:\s*([\d.]+)\s+Lock_time:\s*([\d.]+)\s+Rows_sent:\s*(\d+)\s+Rows_examined:\s*(\d+)`)
+	literalRe    = regexp.MustCompile(`'[^']*'|"[^"]*"|\b\d+\b`)
+)
+
+// Parser incrementally reads a MySQL slow-query log and emits one Event per
+// entry. It is not safe for concurrent use.
+//
+// A Parser is meant to be kept alive for as long as its underlying reader
+// is: Next may return io.EOF with an entry's header fields already consumed
+// but its query line not yet written, and ev/haveStats carry that partial
+// entry forward to the next call rather than losing it.
+type Parser struct {
+	r *bufio.Reader
+
+	// pending holds a line that hadn't been terminated by a newline yet the
+	// last time it was read, so it can be completed once more data arrives.
+	pending string
+
+	ev        Event
+	haveStats bool
+}
+
+// NewParser returns a Parser reading from r, typically a tail of the
+// mysqld slow-log file.
+func NewParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReader(r)}
+}
+
+// Next returns the next parsed Event, or io.EOF once everything currently
+// available from the underlying reader has been consumed. Calling Next
+// again after io.EOF resumes from where it left off, picking up any data
+// appended to the reader in the meantime.
+func (p *Parser) Next() (Event, error) {
+	for {
+		line, err := p.nextLine()
+		if err != nil {
+			return Event{}, err
+		}
+
+		switch {
+		case timeRe.MatchString(line):
+			m := timeRe.FindStringSubmatch(line)
+			if t, err := time.Parse(time.RFC3339Nano, m[1]); err == nil {
+				p.ev.Time = t
+			}
+		case userHostRe.MatchString(line):
+			m := userHostRe.FindStringSubmatch(line)
+			p.ev.User = m[1]
+			p.ev.Host = m[2]
+		case queryStatsRe.MatchString(line):
+			m := queryStatsRe.FindStringSubmatch(line)
+			p.ev.QueryTime, _ = strconv.ParseFloat(m[1], 64)
+			p.ev.LockTime, _ = strconv.ParseFloat(m[2], 64)
+			p.ev.RowsSent, _ = strconv.ParseInt(m[3], 10, 64)
+			p.ev.RowsExamined, _ = strconv.ParseInt(m[4], 10, 64)
+			p.haveStats = true
+		case strings.HasPrefix(line, "#"):
+			// ignore other header lines, e.g. "# Schema:"
+		case strings.HasPrefix(line, "use "):
+			// "use <db>;" is written before SET timestamp=, not the query
+		case strings.HasPrefix(line, "SET timestamp="):
+			// the statement that follows is the actual query
+		case p.haveStats && line != "":
+			ev := p.ev
+			ev.Query = strings.TrimSuffix(strings.TrimSpace(line), ";")
+			ev.Digest = Digest(ev.Query)
+			p.ev = Event{}
+			p.haveStats = false
+			return ev, nil
+		}
+	}
+}
+
+// nextLine returns the next newline-terminated line, with the newline
+// stripped, or io.EOF if nothing currently available completes one. A line
+// read without a trailing newline (the writer hasn't flushed it yet) is
+// held in p.pending and prefixed onto the next read instead of being
+// returned as if it were complete.
+func (p *Parser) nextLine() (string, error) {
+	s, err := p.r.ReadString('\n')
+	if err != nil {
+		if err != io.EOF {
+			return "", err
+		}
+		if s != "" {
+			p.pending += s
+		}
+		return "", io.EOF
+	}
+
+	if p.pending != "" {
+		s = p.pending + s
+		p.pending = ""
+	}
+	return strings.TrimRight(s, "\r\n"), nil
+}
+
+// Digest normalizes literals out of query and returns a short hex hash
+// identifying its shape, so that e.g. "WHERE id = 1" and "WHERE id = 2"
+// are grouped together.
+func Digest(query string) string {
+	normalized := literalRe.ReplaceAllString(query, "?")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SamplingRate parses rate as a fraction in [0,1] and returns it. An empty
+// rate means 1; a value that does not parse or is out of range is an error.
+func SamplingRate(rate string) (float64, error) {
+	if rate == "" {
+		return 1, nil
+	}
+	v, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sampling rate %q: %w", rate, err)
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("sampling rate %q out of range [0,1]", rate)
+	}
+	return v, nil
+}
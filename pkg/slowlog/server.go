@@ -0,0 +1,24 @@
+package slowlog
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler serves the top-N heaviest digests as JSON over /slowlog.
+type Handler struct {
+	table *DigestTable
+	topN  int
+}
+
+// NewHandler returns an http.Handler backed by table, capping responses at topN entries.
+func NewHandler(table *DigestTable, topN int) *Handler {
+	return &Handler{table: table, topN: topN}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.table.TopN(h.topN)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,55 @@
+package slowlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Follow runs fn once for every Event newly appended to path, polling for
+// new data until ctx is canceled. It seeks to the end of the file on
+// startup so that only queries logged after the sidecar starts are
+// processed. samplingRate, in [0,1], is the fraction of events passed to
+// fn; the rest are parsed (so metrics/digests never see a gap bigger than
+// dropping the event entirely) but discarded before the fn callback, so
+// callers can bound the cost of exporting very high query rates.
+func Follow(ctx context.Context, path string, samplingRate float64, fn func(Event)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open slow-log file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("unable to seek to end of %s: %w", path, err)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// One Parser for the whole life of Follow: an entry whose lines straddle
+	// two ticks (header fields read on one tick, the query line itself not
+	// yet written until the next) must keep its partially-read state rather
+	// than losing it to a fresh Parser.
+	parser := NewParser(f)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for {
+				ev, err := parser.Next()
+				if err != nil {
+					break
+				}
+				if samplingRate >= 1 || rand.Float64() < samplingRate {
+					fn(ev)
+				}
+			}
+		}
+	}
+}